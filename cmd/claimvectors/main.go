@@ -0,0 +1,218 @@
+// Command claimvectors generates extra, randomly-sampled range,
+// verification-sampling, and merkle-root vectors to supplement the
+// hand-curated corpus at eth/testvectors/data/claim_vectors.json. Its
+// reference* functions mirror eth.BasicClaimManager's current
+// makeRanges/shouldVerifySegment logic (they are not an independent
+// implementation), so they only make good expected-output labels for
+// freshly-generated vectors - they can't catch a regression in that logic
+// themselves, the way the hand-picked vectors in the checked-in corpus can.
+// genMerkleRootVectors is different: it generates against
+// testvectors.ReferenceMerkleRoot, which is itself a standalone algorithm
+// rather than a mirror of unavailable production code, so those vectors
+// don't share that caveat. Running this tool writes to -out (default below),
+// a separate file from the checked-in corpus; merge vectors you want to keep
+// into claim_vectors.json by hand rather than overwriting it.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/livepeer/go-livepeer/eth/testvectors"
+)
+
+func main() {
+	seed := flag.Int64("seed", 1, "seed for the deterministic RNG used to generate sparse segment bitmaps")
+	out := flag.String("out", "eth/testvectors/data/claim_vectors_generated.json", "path to write the generated supplementary vectors to (deliberately not claim_vectors.json, which is hand-curated)")
+	numRangeVectors := flag.Int("ranges", 4, "number of makeRanges vectors to generate")
+	numSampleVectors := flag.Int("samples", 3, "number of shouldVerifySegment vectors to generate")
+	numMerkleVectors := flag.Int("merkle-roots", 3, "number of merkle root vectors to generate")
+	flag.Parse()
+
+	corpus := &testvectors.Corpus{
+		ProtocolVersion: "v0",
+		Ranges:          genRangeVectors(*seed, *numRangeVectors),
+		VerifySamples:   genVerifySampleVectors(*seed, *numSampleVectors),
+		MerkleRoots:     genMerkleRootVectors(*seed, *numMerkleVectors),
+	}
+
+	if err := testvectors.Save(*out, corpus); err != nil {
+		fmt.Fprintf(os.Stderr, "claimvectors: failed to write corpus: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+var profileNames = []string{"P240p", "P360p", "P480p"}
+
+func genRangeVectors(seed int64, n int) []testvectors.RangeVector {
+	rng := rand.New(rand.NewSource(seed))
+	vectors := make([]testvectors.RangeVector, 0, n)
+
+	for i := 0; i < n; i++ {
+		numProfiles := 1 + rng.Intn(len(profileNames))
+		profiles := append([]string{}, profileNames[:numProfiles]...)
+
+		numSegs := 3 + rng.Intn(8)
+		seqNo := int64(rng.Intn(5))
+		segments := make([]testvectors.SegmentVector, 0, numSegs)
+		for j := 0; j < numSegs; j++ {
+			present := append([]string{}, profiles...)
+			// Occasionally drop a profile to exercise the missing-tDataHash path.
+			if rng.Intn(4) == 0 && len(present) > 1 {
+				present = present[:len(present)-1]
+			}
+			segments = append(segments, testvectors.SegmentVector{SeqNo: seqNo, ProfilesPresent: present})
+
+			seqNo++
+			// Occasionally leave a gap to exercise the discontiguity path.
+			if rng.Intn(4) == 0 {
+				seqNo++
+			}
+		}
+
+		vectors = append(vectors, testvectors.RangeVector{
+			Name:           fmt.Sprintf("generated-%d", i),
+			Profiles:       profiles,
+			Segments:       segments,
+			ExpectedRanges: referenceMakeRanges(profiles, segments),
+		})
+	}
+
+	return vectors
+}
+
+// referenceMakeRanges mirrors eth.BasicClaimManager.makeRanges exactly, so
+// that genRangeVectors can label a randomly-generated vector with its
+// expected output. It is not an independent check on makeRanges - a bug in
+// makeRanges would be copied here too.
+func referenceMakeRanges(profiles []string, segments []testvectors.SegmentVector) [][2]int64 {
+	present := make(map[int64]map[string]bool, len(segments))
+	keys := make([]int64, 0, len(segments))
+	for _, seg := range segments {
+		keys = append(keys, seg.SeqNo)
+		set := make(map[string]bool, len(seg.ProfilesPresent))
+		for _, p := range seg.ProfilesPresent {
+			set[p] = true
+		}
+		present[seg.SeqNo] = set
+	}
+
+	if len(keys) == 0 {
+		return [][2]int64{}
+	}
+
+	for i := 0; i < len(keys); i++ {
+		for j := i + 1; j < len(keys); j++ {
+			if keys[j] < keys[i] {
+				keys[i], keys[j] = keys[j], keys[i]
+			}
+		}
+	}
+
+	start := keys[0]
+	ranges := make([][2]int64, 0)
+	for i, key := range keys {
+		startNewRange := false
+		for _, p := range profiles {
+			if !present[key][p] {
+				ranges = append(ranges, [2]int64{start, keys[i-1]})
+				startNewRange = true
+				break
+			}
+		}
+
+		if !startNewRange && (i+1 == len(keys) || keys[i+1] != keys[i]+1) {
+			ranges = append(ranges, [2]int64{start, keys[i]})
+			startNewRange = true
+		}
+
+		if startNewRange && i+1 != len(keys) {
+			start = keys[i+1]
+		}
+	}
+
+	return ranges
+}
+
+func genVerifySampleVectors(seed int64, n int) []testvectors.VerifySampleVector {
+	rng := rand.New(rand.NewSource(seed + 1))
+	vectors := make([]testvectors.VerifySampleVector, 0, n)
+
+	for i := 0; i < n; i++ {
+		start := int64(rng.Intn(1000))
+		end := start + int64(rng.Intn(20))
+		blkNum := int64(rng.Intn(1_000_000))
+		verifyRate := uint64(1 + rng.Intn(10))
+		plusOneBlkHash := crypto.Keccak256Hash([]byte(fmt.Sprintf("vector-%d", i)))
+
+		challenged := []int64{}
+		for seqNo := start; seqNo <= end; seqNo++ {
+			if referenceShouldVerifySegment(seqNo, start, end, blkNum, plusOneBlkHash, verifyRate) {
+				challenged = append(challenged, seqNo)
+			}
+		}
+
+		vectors = append(vectors, testvectors.VerifySampleVector{
+			Name:               fmt.Sprintf("generated-%d", i),
+			RangeStart:         start,
+			RangeEnd:           end,
+			ClaimBlkNum:        blkNum,
+			PlusOneBlkHash:     plusOneBlkHash.Hex()[2:],
+			VerifyRate:         verifyRate,
+			ExpectedChallenged: challenged,
+		})
+	}
+
+	return vectors
+}
+
+// genMerkleRootVectors generates vectors for testvectors.ReferenceMerkleRoot
+// rather than a reference implementation of it (unlike genRangeVectors and
+// genVerifySampleVectors above), since ReferenceMerkleRoot is itself already
+// a standalone algorithm defined in this tree, not a mirror of unavailable
+// production code - see testvectors.ReferenceMerkleRoot's doc comment.
+func genMerkleRootVectors(seed int64, n int) []testvectors.MerkleRootVector {
+	rng := rand.New(rand.NewSource(seed + 2))
+	vectors := make([]testvectors.MerkleRootVector, 0, n)
+
+	for i := 0; i < n; i++ {
+		numLeaves := 1 + rng.Intn(6)
+		leaves := make([]common.Hash, numLeaves)
+		hashes := make([]string, numLeaves)
+		for j := 0; j < numLeaves; j++ {
+			leaves[j] = crypto.Keccak256Hash([]byte(fmt.Sprintf("generated-receipt-%d-%d", i, j)))
+			hashes[j] = leaves[j].Hex()[2:]
+		}
+
+		vectors = append(vectors, testvectors.MerkleRootVector{
+			Name:          fmt.Sprintf("generated-%d", i),
+			ReceiptHashes: hashes,
+			ExpectedRoot:  testvectors.ReferenceMerkleRoot(leaves).Hex()[2:],
+		})
+	}
+
+	return vectors
+}
+
+// referenceShouldVerifySegment mirrors
+// eth.BasicClaimManager.shouldVerifySegment exactly, for the same labeling
+// purpose and with the same caveat as referenceMakeRanges above.
+func referenceShouldVerifySegment(seqNum, start, end, blkNum int64, plusOneBlkHash common.Hash, verifyRate uint64) bool {
+	if seqNum < start || seqNum > end {
+		return false
+	}
+
+	bigSeqNumBytes := common.LeftPadBytes(new(big.Int).SetInt64(seqNum).Bytes(), 32)
+	bigBlkNumBytes := common.LeftPadBytes(new(big.Int).SetInt64(blkNum+1).Bytes(), 32)
+
+	combH := crypto.Keccak256(bigBlkNumBytes, plusOneBlkHash.Bytes(), bigSeqNumBytes)
+	hashNum := new(big.Int).SetBytes(combH)
+	result := new(big.Int).Mod(hashNum, new(big.Int).SetInt64(int64(verifyRate)))
+
+	return result.Cmp(big.NewInt(0)) == 0
+}
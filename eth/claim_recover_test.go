@@ -0,0 +1,81 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/livepeer/go-livepeer/eth/claimstore"
+)
+
+// fakeClaimStore is a minimal in-memory claimstore.ClaimStore for tests that
+// only need Recover's bookkeeping, not actual persistence.
+type fakeClaimStore struct {
+	receipts map[int64]*claimstore.ReceiptRecord
+	claims   map[int64]*claimstore.ClaimRecord
+}
+
+func newFakeClaimStore() *fakeClaimStore {
+	return &fakeClaimStore{
+		receipts: make(map[int64]*claimstore.ReceiptRecord),
+		claims:   make(map[int64]*claimstore.ClaimRecord),
+	}
+}
+
+func (s *fakeClaimStore) SaveReceipt(prefix string, rec *claimstore.ReceiptRecord) error {
+	s.receipts[rec.SeqNo] = rec
+	return nil
+}
+
+func (s *fakeClaimStore) Receipts(prefix string) (map[int64]*claimstore.ReceiptRecord, error) {
+	return s.receipts, nil
+}
+
+func (s *fakeClaimStore) SaveClaim(prefix string, rec *claimstore.ClaimRecord) error {
+	s.claims[rec.ClaimID] = rec
+	return nil
+}
+
+func (s *fakeClaimStore) UpdateClaimState(prefix string, claimID int64, state claimstore.ClaimState) error {
+	rec, ok := s.claims[claimID]
+	if !ok {
+		return nil
+	}
+	rec.State = state
+	return nil
+}
+
+func (s *fakeClaimStore) Claims(prefix string) (map[int64]*claimstore.ClaimRecord, error) {
+	return s.claims, nil
+}
+
+func (s *fakeClaimStore) Close() error { return nil }
+
+// TestRecoverClearsUnclaimedSegsForCompletedClaims reproduces the bug where a
+// claim that already reached FeesDistributed before a crash left its segments
+// in unclaimedSegs, so the next ClaimVerifyAndDistributeFees call would
+// re-claim (and double-pay for) work that was already settled.
+func TestRecoverClearsUnclaimedSegsForCompletedClaims(t *testing.T) {
+	store := newFakeClaimStore()
+
+	for seqNo := int64(0); seqNo <= 2; seqNo++ {
+		store.receipts[seqNo] = &claimstore.ReceiptRecord{SeqNo: seqNo}
+	}
+	store.claims[0] = &claimstore.ClaimRecord{
+		ClaimID:  0,
+		SegRange: [2]int64{0, 2},
+		State:    claimstore.FeesDistributed,
+	}
+
+	cm, err := Recover(context.Background(), "strm", big.NewInt(1), common.Address{}, big.NewInt(0), nil, nil, nil, store)
+	if err != nil {
+		t.Fatalf("Recover returned error: %v", err)
+	}
+
+	for seqNo := int64(0); seqNo <= 2; seqNo++ {
+		if cm.unclaimedSegs[seqNo] {
+			t.Errorf("segment %v still marked unclaimed after its claim reached FeesDistributed", seqNo)
+		}
+	}
+}
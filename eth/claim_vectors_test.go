@@ -0,0 +1,120 @@
+package eth
+
+import (
+	"encoding/hex"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/livepeer/go-livepeer/eth/testvectors"
+	lpmscore "github.com/livepeer/lpms/core"
+)
+
+func loadClaimVectorCorpus(t *testing.T) *testvectors.Corpus {
+	corpus, err := testvectors.Load(filepath.Join("testvectors", "data", "claim_vectors.json"))
+	if err != nil {
+		t.Fatalf("failed to load claim vector corpus: %v", err)
+	}
+	return corpus
+}
+
+func TestMakeRangesConformsToVectors(t *testing.T) {
+	corpus := loadClaimVectorCorpus(t)
+
+	for _, v := range corpus.Ranges {
+		v := v
+		t.Run(v.Name, func(t *testing.T) {
+			profiles := make([]lpmscore.VideoProfile, len(v.Profiles))
+			for i, name := range v.Profiles {
+				profiles[i] = lpmscore.VideoProfile{Name: name}
+			}
+
+			cm := &BasicClaimManager{
+				profiles:      profiles,
+				unclaimedSegs: make(map[int64]bool),
+				segClaimMap:   make(map[int64]*claimData),
+			}
+
+			for _, seg := range v.Segments {
+				cd := &claimData{seqNo: seg.SeqNo, tDataHashes: make(map[lpmscore.VideoProfile][]byte)}
+				for _, name := range seg.ProfilesPresent {
+					cd.tDataHashes[lpmscore.VideoProfile{Name: name}] = []byte{0x1}
+				}
+				cm.segClaimMap[seg.SeqNo] = cd
+				cm.unclaimedSegs[seg.SeqNo] = true
+			}
+
+			got := cm.makeRanges()
+			if len(got) != len(v.ExpectedRanges) {
+				t.Fatalf("got %v ranges, want %v", got, v.ExpectedRanges)
+			}
+			for i, r := range got {
+				if r[0] != v.ExpectedRanges[i][0] || r[1] != v.ExpectedRanges[i][1] {
+					t.Errorf("range %v: got %v, want %v", i, r, v.ExpectedRanges[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReferenceMerkleRootConformsToVectors(t *testing.T) {
+	corpus := loadClaimVectorCorpus(t)
+
+	for _, v := range corpus.MerkleRoots {
+		v := v
+		t.Run(v.Name, func(t *testing.T) {
+			leaves := make([]common.Hash, len(v.ReceiptHashes))
+			for i, h := range v.ReceiptHashes {
+				hashBytes, err := hex.DecodeString(h)
+				if err != nil {
+					t.Fatalf("bad receiptHashes[%v] in vector: %v", i, err)
+				}
+				leaves[i] = common.BytesToHash(hashBytes)
+			}
+
+			got := testvectors.ReferenceMerkleRoot(leaves)
+			if got.Hex()[2:] != v.ExpectedRoot {
+				t.Errorf("got root %v, want %v", got.Hex()[2:], v.ExpectedRoot)
+			}
+		})
+	}
+}
+
+func TestShouldVerifySegmentConformsToVectors(t *testing.T) {
+	corpus := loadClaimVectorCorpus(t)
+	cm := &BasicClaimManager{}
+
+	for _, v := range corpus.VerifySamples {
+		v := v
+		t.Run(v.Name, func(t *testing.T) {
+			hashBytes, err := hex.DecodeString(v.PlusOneBlkHash)
+			if err != nil {
+				t.Fatalf("bad plusOneBlkHash in vector: %v", err)
+			}
+			plusOneBlkHash := common.BytesToHash(hashBytes)
+
+			challenged := []int64{}
+			for seqNo := v.RangeStart; seqNo <= v.RangeEnd; seqNo++ {
+				if cm.shouldVerifySegment(seqNo, v.RangeStart, v.RangeEnd, v.ClaimBlkNum, plusOneBlkHash, v.VerifyRate) {
+					challenged = append(challenged, seqNo)
+				}
+			}
+
+			if v.ProbeSeqNo != nil {
+				probe := cm.shouldVerifySegment(*v.ProbeSeqNo, v.RangeStart, v.RangeEnd, v.ClaimBlkNum, plusOneBlkHash, v.VerifyRate)
+				if probe {
+					t.Errorf("probe seqNo %v: expected not to be challenged (outside range)", *v.ProbeSeqNo)
+				}
+			}
+
+			if len(challenged) != len(v.ExpectedChallenged) {
+				t.Fatalf("got challenged %v, want %v", challenged, v.ExpectedChallenged)
+			}
+			for i := range challenged {
+				if challenged[i] != v.ExpectedChallenged[i] {
+					t.Errorf("challenged[%v]: got %v, want %v", i, challenged[i], v.ExpectedChallenged[i])
+				}
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package eth
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"math/big"
@@ -12,8 +11,9 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/eth/claimstore"
+	"github.com/livepeer/go-livepeer/eth/segmentstore"
 	ethTypes "github.com/livepeer/go-livepeer/eth/types"
-	"github.com/livepeer/go-livepeer/ipfs"
 	lpmscore "github.com/livepeer/lpms/core"
 )
 
@@ -24,7 +24,7 @@ var (
 type ClaimManager interface {
 	AddReceipt(seqNo int64, data []byte, tDataHash []byte, bSig []byte, profile lpmscore.VideoProfile) error
 	SufficientBroadcasterDeposit() (bool, error)
-	ClaimVerifyAndDistributeFees() error
+	ClaimVerifyAndDistributeFees(ctx context.Context) error
 	CanClaim() (bool, error)
 	DidFirstClaim() bool
 }
@@ -41,8 +41,13 @@ type claimData struct {
 
 //BasicClaimManager manages the claim process for a Livepeer transcoder.  Check the Livepeer protocol for more details.
 type BasicClaimManager struct {
-	client LivepeerEthClient
-	ipfs   ipfs.IpfsApi
+	client   LivepeerEthClient
+	segStore segmentstore.SegmentStore
+
+	store     claimstore.ClaimStore
+	keyPrefix string
+
+	pipelineCfg PipelineConfig
 
 	strmID   string
 	jobID    *big.Int
@@ -58,10 +63,33 @@ type BasicClaimManager struct {
 
 	claims     int64
 	claimsLock sync.Mutex
+
+	// submitLock serializes a job's on-chain claim submissions (ClaimWork /
+	// ClaimWorkBatch plus the GetClaim call that learns the assigned claim
+	// ID) across submit-claim workers, so claim IDs are handed out in the
+	// order transactions are confirmed rather than in whatever order
+	// concurrent CheckTx calls happen to return. This is only needed when
+	// client doesn't implement ClaimIDReporter; a client that does lets
+	// submitRangeClaim/submitBatchedClaimWork learn each range's claim ID
+	// straight from its own transaction and skip this lock entirely, so
+	// submissions stay concurrent.
+	submitLock sync.Mutex
 }
 
-//NewBasicClaimManager creates a new claim manager.
-func NewBasicClaimManager(sid string, jid *big.Int, broadcaster common.Address, pricePerSegment *big.Int, p []lpmscore.VideoProfile, c LivepeerEthClient, ipfs ipfs.IpfsApi) *BasicClaimManager {
+// jobKeyPrefix returns the key prefix a ClaimStore uses to scope all state for jid.
+func jobKeyPrefix(jid *big.Int) string {
+	return fmt.Sprintf("job-%v", jid)
+}
+
+//NewBasicClaimManager creates a new claim manager. store may be nil, in which
+//case claim state is kept in memory only and cannot be recovered after a
+//crash; callers that want crash recovery should pass a ClaimStore (e.g.
+//claimstore.NewBoltClaimStore) and use Recover on startup instead of this
+//constructor for jobs that may already have persisted state. segStore
+//publishes challenged segment data during verification; segmentstore.NewMultiStore
+//wrapping segmentstore.NewIPFSStore preserves the manager's original
+//IPFS-only behavior.
+func NewBasicClaimManager(sid string, jid *big.Int, broadcaster common.Address, pricePerSegment *big.Int, p []lpmscore.VideoProfile, c LivepeerEthClient, segStore segmentstore.SegmentStore, store claimstore.ClaimStore) *BasicClaimManager {
 	seqNos := make([][]int64, len(p), len(p))
 	rHashes := make([][]common.Hash, len(p), len(p))
 	sd := make([][][]byte, len(p), len(p))
@@ -89,7 +117,10 @@ func NewBasicClaimManager(sid string, jid *big.Int, broadcaster common.Address,
 
 	return &BasicClaimManager{
 		client:          c,
-		ipfs:            ipfs,
+		segStore:        segStore,
+		store:           store,
+		keyPrefix:       jobKeyPrefix(jid),
+		pipelineCfg:     DefaultPipelineConfig,
 		strmID:          sid,
 		jobID:           jid,
 		cost:            big.NewInt(0),
@@ -103,6 +134,90 @@ func NewBasicClaimManager(sid string, jid *big.Int, broadcaster common.Address,
 	}
 }
 
+//SetPipelineConfig overrides the worker pool sizes ClaimVerifyAndDistributeFees
+//uses for its submit-claim, verify, and distribute-fees stages. Safe to call
+//any time before ClaimVerifyAndDistributeFees is invoked.
+func (c *BasicClaimManager) SetPipelineConfig(cfg PipelineConfig) {
+	c.pipelineCfg = cfg
+}
+
+//Recover rehydrates a BasicClaimManager for an in-flight job from store,
+//restoring any receipts that were persisted via AddReceipt and resuming any
+//claims that had not yet reached FeesDistributed when the process stopped
+//through the same bounded ClaimPipeline that ClaimVerifyAndDistributeFees
+//uses, via ClaimPipeline.RunRecovered. The caller must still supply the
+//job's parameters (stream ID, profiles, pricing) since ClaimStore only
+//persists claim lifecycle state, not the job itself. ctx bounds the
+//recovery run the same way it bounds ClaimVerifyAndDistributeFees; Recover
+//itself returns as soon as cm is rehydrated, without waiting for recovery
+//to finish.
+func Recover(ctx context.Context, sid string, jid *big.Int, broadcaster common.Address, pricePerSegment *big.Int, p []lpmscore.VideoProfile, c LivepeerEthClient, segStore segmentstore.SegmentStore, store claimstore.ClaimStore) (*BasicClaimManager, error) {
+	cm := NewBasicClaimManager(sid, jid, broadcaster, pricePerSegment, p, c, segStore, store)
+
+	receipts, err := store.Receipts(cm.keyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	for seqNo, rec := range receipts {
+		cd := &claimData{
+			seqNo:       rec.SeqNo,
+			segData:     rec.SegData,
+			dataHash:    rec.DataHash,
+			tDataHashes: make(map[lpmscore.VideoProfile][]byte),
+			bSig:        rec.BSig,
+		}
+		for _, prof := range p {
+			if h, ok := rec.TDataHashes[prof.Name]; ok {
+				cd.tDataHashes[prof] = h
+			}
+		}
+		cm.segClaimMap[seqNo] = cd
+		cm.unclaimedSegs[seqNo] = true
+	}
+
+	claims, err := store.Claims(cm.keyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var toResume []*claimstore.ClaimRecord
+	for _, rec := range claims {
+		if int64(len(claims)) > cm.claims {
+			cm.claims = int64(len(claims))
+		}
+
+		// Segments covered by any persisted claim are already spoken for,
+		// regardless of how far that claim got - leaving them in
+		// unclaimedSegs would make the next ClaimVerifyAndDistributeFees
+		// call re-claim (and double-pay) work that's already done.
+		for segNo := rec.SegRange[0]; segNo <= rec.SegRange[1]; segNo++ {
+			delete(cm.unclaimedSegs, segNo)
+			if seg, ok := cm.segClaimMap[segNo]; ok {
+				seg.transcodeProof = rec.MerkleProofs[segNo]
+				seg.claimConcatTDatahash = rec.ConcatHashes[segNo]
+			}
+		}
+
+		if rec.State == claimstore.FeesDistributed {
+			continue
+		}
+
+		toResume = append(toResume, rec)
+	}
+
+	if len(toResume) > 0 {
+		pipeline := NewClaimPipeline(cm, cm.pipelineCfg)
+		go func() {
+			if errs := pipeline.RunRecovered(ctx, toResume); len(errs) > 0 {
+				glog.Errorf("claim pipeline: %v of %v recovered claim(s) failed to resume, first error: %v", len(errs), len(toResume), errs[0])
+			}
+		}()
+	}
+
+	return cm, nil
+}
+
 func (c *BasicClaimManager) CanClaim() (bool, error) {
 	// A transcoder can claim if:
 	// - There are unclaimed segments
@@ -165,9 +280,32 @@ func (c *BasicClaimManager) AddReceipt(seqNo int64, data []byte, tDataHash []byt
 	c.cost = new(big.Int).Add(c.cost, c.pricePerSegment)
 	c.unclaimedSegs[seqNo] = true
 
+	if c.store != nil {
+		if err := c.store.SaveReceipt(c.keyPrefix, cd.toReceiptRecord()); err != nil {
+			glog.Errorf("Error persisting receipt for segment %v: %v", seqNo, err)
+			storeErrors.Inc()
+		}
+	}
+
 	return nil
 }
 
+//toReceiptRecord converts a claimData into the form persisted by a ClaimStore.
+func (cd *claimData) toReceiptRecord() *claimstore.ReceiptRecord {
+	tHashes := make(map[string][]byte)
+	for p, h := range cd.tDataHashes {
+		tHashes[p.Name] = h
+	}
+
+	return &claimstore.ReceiptRecord{
+		SeqNo:       cd.seqNo,
+		SegData:     cd.segData,
+		DataHash:    cd.dataHash,
+		TDataHashes: tHashes,
+		BSig:        cd.bSig,
+	}
+}
+
 func (c *BasicClaimManager) SufficientBroadcasterDeposit() (bool, error) {
 	bDeposit, err := c.client.BroadcasterDeposit(c.broadcasterAddr)
 	if err != nil {
@@ -236,116 +374,299 @@ func (c *BasicClaimManager) markClaimedSegs(segRange [2]int64) {
 	}
 }
 
-//Claim creates the onchain claim for all the claims added through AddReceipt
-func (c *BasicClaimManager) ClaimVerifyAndDistributeFees() error {
+//ClaimVerifyAndDistributeFees claims all outstanding ranges added through
+//AddReceipt and runs them through submit-claim, verify, and
+//distribute-fees via a ClaimPipeline sized by c.pipelineCfg (see
+//SetPipelineConfig). It returns once every range has been processed or ctx
+//is canceled, aggregating any per-range failures instead of letting one
+//range's error block or abandon the others.
+func (c *BasicClaimManager) ClaimVerifyAndDistributeFees(ctx context.Context) error {
 	ranges := c.makeRanges()
 
-	for _, segRange := range ranges {
-		//create concat hashes for each seg
-		receiptHashes := make([]common.Hash, segRange[1]-segRange[0]+1)
-		for i := segRange[0]; i <= segRange[1]; i++ {
-			segTDataHashes := make([][]byte, len(c.profiles))
-			for pi, p := range c.profiles {
-				segTDataHashes[pi] = []byte(c.segClaimMap[i].tDataHashes[p])
-			}
-			seg, _ := c.segClaimMap[i]
-			seg.claimConcatTDatahash = crypto.Keccak256(segTDataHashes...)
-
-			receipt := &ethTypes.TranscodeReceipt{
-				StreamID:                 c.strmID,
-				SegmentSequenceNumber:    big.NewInt(seg.seqNo),
-				DataHash:                 seg.dataHash,
-				ConcatTranscodedDataHash: seg.claimConcatTDatahash,
-				BroadcasterSig:           seg.bSig,
-			}
+	pipeline := NewClaimPipeline(c, c.pipelineCfg)
+	if errs := pipeline.Run(ctx, ranges); len(errs) > 0 {
+		return fmt.Errorf("claim pipeline: %v of %v range(s) failed, first error: %v", len(errs), len(ranges), errs[0])
+	}
+
+	return nil
+}
+
+//rangeMerkle is the result of building the merkle tree for a range: the
+//root to submit on-chain, and the per-segment proof/concat-hash bytes
+//needed to verify later and to persist in a ClaimRecord.
+type rangeMerkle struct {
+	segRange     [2]int64
+	rootHash     common.Hash
+	proofBytes   map[int64][]byte
+	concatHashes map[int64][]byte
+}
 
-			receiptHashes[i-segRange[0]] = receipt.Hash()
+//buildRangeMerkle computes the merkle tree for segRange. This is pure local
+//computation (no chain calls), so ClaimPipeline does it before a range ever
+//reaches a submit-claim worker.
+func (c *BasicClaimManager) buildRangeMerkle(segRange [2]int64) (*rangeMerkle, error) {
+	receiptHashes := make([]common.Hash, segRange[1]-segRange[0]+1)
+	for i := segRange[0]; i <= segRange[1]; i++ {
+		segTDataHashes := make([][]byte, len(c.profiles))
+		for pi, p := range c.profiles {
+			segTDataHashes[pi] = []byte(c.segClaimMap[i].tDataHashes[p])
+		}
+		seg, _ := c.segClaimMap[i]
+		seg.claimConcatTDatahash = crypto.Keccak256(segTDataHashes...)
+
+		receipt := &ethTypes.TranscodeReceipt{
+			StreamID:                 c.strmID,
+			SegmentSequenceNumber:    big.NewInt(seg.seqNo),
+			DataHash:                 seg.dataHash,
+			ConcatTranscodedDataHash: seg.claimConcatTDatahash,
+			BroadcasterSig:           seg.bSig,
 		}
 
-		//create merkle root for concat hashes
-		root, proofs, err := ethTypes.NewMerkleTree(receiptHashes)
-		if err != nil {
-			glog.Errorf("Error: %v - creating merkle root for %v", err, receiptHashes)
-			continue
+		receiptHashes[i-segRange[0]] = receipt.Hash()
+	}
+
+	root, proofs, err := ethTypes.NewMerkleTree(receiptHashes)
+	if err != nil {
+		return nil, fmt.Errorf("error creating merkle root for %v: %v", segRange, err)
+	}
+
+	proofBytes := make(map[int64][]byte)
+	concatHashes := make(map[int64][]byte)
+	for i := segRange[0]; i <= segRange[1]; i++ {
+		seg, _ := c.segClaimMap[i]
+		seg.transcodeProof = proofs[i-segRange[0]].Bytes()
+		proofBytes[i] = seg.transcodeProof
+		concatHashes[i] = seg.claimConcatTDatahash
+	}
+
+	return &rangeMerkle{segRange: segRange, rootHash: root.Hash, proofBytes: proofBytes, concatHashes: concatHashes}, nil
+}
+
+//rangeResult is the outcome of submitting a single range's claim, kept
+//alongside its segRange so a failure can be reported without losing track
+//of which ranges in a batch succeeded.
+type rangeResult struct {
+	segRange [2]int64
+	rec      *claimstore.ClaimRecord
+	err      error
+}
+
+//submitRangeBatch submits a batch of ranges' claims on-chain, packing them
+//into a single ClaimWorkBatch transaction when c.client supports
+//BatchClaimant, and falling back to one ClaimWork call per range otherwise
+//(including when the batched call itself fails).
+func (c *BasicClaimManager) submitRangeBatch(batch []*rangeMerkle) []rangeResult {
+	if len(batch) > 1 {
+		if batcher, ok := c.client.(BatchClaimant); ok {
+			results, err := c.submitBatchedClaimWork(batcher, batch)
+			if err == nil {
+				return results
+			}
+			glog.Errorf("Error submitting batched claim work, falling back to per-range submission: %v", err)
 		}
+	}
+
+	results := make([]rangeResult, len(batch))
+	for i, rm := range batch {
+		rec, err := c.submitRangeClaim(rm)
+		results[i] = rangeResult{segRange: rm.segRange, rec: rec, err: err}
+	}
+	return results
+}
+
+//submitBatchedClaimWork packs every range in batch into one ClaimWorkBatch
+//transaction. If c.client implements ClaimIDReporter, the assigned claim IDs
+//come straight back out of the confirmed transaction and this runs
+//concurrently with other submit workers; otherwise the whole
+//submit-and-finalize sequence runs under submitLock (see
+//BasicClaimManager.submitLock) so a second submit worker can't fetch and
+//assign a claim ID out of order with this one.
+func (c *BasicClaimManager) submitBatchedClaimWork(batcher BatchClaimant, batch []*rangeMerkle) ([]rangeResult, error) {
+	reporter, hasReporter := c.client.(ClaimIDReporter)
+	if !hasReporter {
+		c.submitLock.Lock()
+		defer c.submitLock.Unlock()
+	}
+
+	bigRanges := make([][2]*big.Int, len(batch))
+	roots := make([]common.Hash, len(batch))
+	for i, rm := range batch {
+		bigRanges[i] = [2]*big.Int{big.NewInt(rm.segRange[0]), big.NewInt(rm.segRange[1])}
+		roots[i] = rm.rootHash
+	}
+
+	tx, err := batcher.ClaimWorkBatch(c.jobID, bigRanges, roots)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.client.CheckTx(tx); err != nil {
+		return nil, err
+	}
 
-		bigRange := [2]*big.Int{big.NewInt(segRange[0]), big.NewInt(segRange[1])}
-		tx, err := c.client.ClaimWork(c.jobID, bigRange, root.Hash)
+	glog.Infof("Submitted batched transcode claim for %v ranges", len(batch))
+
+	var claimIDs []int64
+	if hasReporter {
+		claimIDs, err = reporter.ClaimIDsForTx(tx)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if len(claimIDs) != len(batch) {
+			return nil, fmt.Errorf("claim manager: tx reported %v claim ID(s) for a batch of %v range(s)", len(claimIDs), len(batch))
 		}
+	}
 
-		err = c.client.CheckTx(tx)
-		if err != nil {
-			return err
+	results := make([]rangeResult, len(batch))
+	for i, rm := range batch {
+		var claimID *big.Int
+		if hasReporter {
+			claimID = big.NewInt(claimIDs[i])
+		} else {
+			claimID = c.nextCounterClaimID()
 		}
+		rec, err := c.finalizeClaimRecord(rm, claimID)
+		results[i] = rangeResult{segRange: rm.segRange, rec: rec, err: err}
+	}
+	return results, nil
+}
+
+//submitRangeClaim submits a single range's claim with its own ClaimWork
+//transaction. This is the path used when BatchClaimant isn't supported, the
+//batch only has one range in it, or the batched submission itself failed.
+//Like submitBatchedClaimWork, it only needs submitLock when c.client doesn't
+//implement ClaimIDReporter.
+func (c *BasicClaimManager) submitRangeClaim(rm *rangeMerkle) (*claimstore.ClaimRecord, error) {
+	reporter, hasReporter := c.client.(ClaimIDReporter)
+	if !hasReporter {
+		c.submitLock.Lock()
+		defer c.submitLock.Unlock()
+	}
 
-		glog.Infof("Submitted transcode claim for segments %v - %v", segRange[0], segRange[1])
+	bigRange := [2]*big.Int{big.NewInt(rm.segRange[0]), big.NewInt(rm.segRange[1])}
+	tx, err := c.client.ClaimWork(c.jobID, bigRange, rm.rootHash)
+	if err != nil {
+		return nil, err
+	}
 
-		c.markClaimedSegs(segRange)
-		c.claims++
+	if err := c.client.CheckTx(tx); err != nil {
+		return nil, err
+	}
 
-		claim, err := c.client.GetClaim(c.jobID, big.NewInt(c.claims-1))
+	glog.Infof("Submitted transcode claim for segments %v - %v", rm.segRange[0], rm.segRange[1])
+
+	var claimID *big.Int
+	if hasReporter {
+		claimIDs, err := reporter.ClaimIDsForTx(tx)
 		if err != nil {
-			return err
+			return nil, err
 		}
-
-		//Record proofs for each segment in case the segment needs to be verified
-		for i := segRange[0]; i <= segRange[1]; i++ {
-			seg, _ := c.segClaimMap[i]
-			seg.transcodeProof = proofs[i-segRange[0]].Bytes()
+		if len(claimIDs) != 1 {
+			return nil, fmt.Errorf("claim manager: tx reported %v claim ID(s) for a single-range submission", len(claimIDs))
 		}
+		claimID = big.NewInt(claimIDs[0])
+	} else {
+		claimID = c.nextCounterClaimID()
+	}
 
-		//Do the claim
-		go func(segRange [2]int64, claim *ethTypes.Claim) {
-			b, err := c.client.Backend()
-			if err != nil {
-				glog.Error(err)
-				return
-			}
+	return c.finalizeClaimRecord(rm, claimID)
+}
 
-			// Wait one block for claimBlock + 1 to be mined
-			Wait(b, RpcTimeout, big.NewInt(1))
+//nextCounterClaimID peeks the claim ID the call-order counter expects the
+//contract to assign next, for use only when c.client doesn't implement
+//ClaimIDReporter. It's only correct because submitBatchedClaimWork/
+//submitRangeClaim hold submitLock across their entire submit-and-finalize
+//sequence in that case (finalizeClaimRecord is what actually advances the
+//counter), so claim IDs are handed out in the same order the contract
+//actually assigned them.
+func (c *BasicClaimManager) nextCounterClaimID() *big.Int {
+	c.claimsLock.Lock()
+	defer c.claimsLock.Unlock()
+	return big.NewInt(c.claims)
+}
 
-			plusOneBlk, err := b.BlockByNumber(context.Background(), new(big.Int).Add(claim.ClaimBlock, big.NewInt(1)))
-			if err != nil {
-				return
-			}
+//finalizeClaimRecord marks a range's segments claimed, counts another claim
+//toward DidFirstClaim, fetches the claim at claimID (already known to be
+//correct for rm - either reported directly by the submitting transaction
+//via ClaimIDReporter, or peeked from nextCounterClaimID under submitLock),
+//and persists the resulting ClaimRecord.
+func (c *BasicClaimManager) finalizeClaimRecord(rm *rangeMerkle, claimID *big.Int) (*claimstore.ClaimRecord, error) {
+	c.claimsLock.Lock()
+	c.markClaimedSegs(rm.segRange)
+	c.claims++
+	c.claimsLock.Unlock()
+
+	claim, err := c.client.GetClaim(c.jobID, claimID)
+	if err != nil {
+		return nil, err
+	}
 
-			// Submit for verification if necessary
-			c.verify(claim.ClaimId, claim.ClaimBlock.Int64(), plusOneBlk.Hash(), segRange)
-			// Distribute fees once verification is complete
-			c.distributeFees(claim.ClaimId)
-		}(segRange, claim)
+	rec := &claimstore.ClaimRecord{
+		ClaimID:      claim.ClaimId.Int64(),
+		SegRange:     rm.segRange,
+		State:        claimstore.Claimed,
+		ClaimBlock:   claim.ClaimBlock.Int64(),
+		MerkleProofs: rm.proofBytes,
+		ConcatHashes: rm.concatHashes,
+	}
+	if c.store != nil {
+		if err := c.store.SaveClaim(c.keyPrefix, rec); err != nil {
+			glog.Errorf("Error persisting claim %v: %v", rec.ClaimID, err)
+			storeErrors.Inc()
+		}
 	}
 
-	return nil
+	return rec, nil
+}
+
+//setClaimState persists a claim state transition, logging rather than
+//failing the in-progress goroutine if the store write itself errors.
+func (c *BasicClaimManager) setClaimState(claimID int64, state claimstore.ClaimState) {
+	if c.store == nil {
+		return
+	}
+	if err := c.store.UpdateClaimState(c.keyPrefix, claimID, state); err != nil {
+		glog.Errorf("Error persisting state %v for claim %v: %v", state, claimID, err)
+		storeErrors.Inc()
+	}
 }
 
-func (c *BasicClaimManager) verify(claimID *big.Int, claimBlkNum int64, plusOneBlkHash common.Hash, segRange [2]int64) error {
+//verify challenges whichever segments in segRange the verification sampling
+//selects. It returns the number of segments it challenged, so pipeline
+//stages can report accurate per-range metrics. ctx bounds the segStore.Put
+//upload (including MultiStore's retry/backoff across its backends), so
+//canceling the pipeline actually stops an in-flight upload instead of
+//running it to completion regardless.
+func (c *BasicClaimManager) verify(ctx context.Context, claimID *big.Int, claimBlkNum int64, plusOneBlkHash common.Hash, segRange [2]int64) (int, error) {
 	//Get verification rate
 	verifyRate, err := c.client.VerificationRate()
 	if err != nil {
 		glog.Errorf("Error getting verification rate: %v", err)
-		return err
+		return 0, err
 	}
 
+	challenged := 0
+
 	//Iterate through segments, determine which one needs to be verified.
 	for segNo := segRange[0]; segNo <= segRange[1]; segNo++ {
 		if c.shouldVerifySegment(segNo, segRange[0], segRange[1], claimBlkNum, plusOneBlkHash, verifyRate) {
 			glog.Infof("Segment %v challenged for verification", segNo)
+			challenged++
 
 			seg := c.segClaimMap[segNo]
 
-			dataStorageHash, err := c.ipfs.Add(bytes.NewReader(seg.segData))
+			dataStorageHash, scheme, contentHash, err := c.segStore.Put(ctx, seg.segData)
 			if err != nil {
-				glog.Errorf("Error uploading segment data to IPFS: %v", err)
+				glog.Errorf("Error uploading segment data to storage backend: %v", err)
+				continue
+			}
+			if contentHash != [32]byte(common.BytesToHash(seg.dataHash)) {
+				glog.Errorf("Segment %v content hash mismatch after upload, skipping verification", segNo)
 				continue
 			}
 
 			dataHashes := [2][32]byte{common.BytesToHash(seg.dataHash), common.BytesToHash(seg.claimConcatTDatahash)}
 
-			tx, err := c.client.Verify(c.jobID, claimID, big.NewInt(segNo), dataStorageHash, dataHashes, seg.bSig, seg.transcodeProof)
+			tx, err := c.client.Verify(c.jobID, claimID, big.NewInt(segNo), dataStorageHash, byte(scheme), dataHashes, seg.bSig, seg.transcodeProof)
 			if err != nil {
 				glog.Errorf("Error submitting segment %v for verification: %v", segNo, err)
 				continue
@@ -361,7 +682,7 @@ func (c *BasicClaimManager) verify(claimID *big.Int, claimBlkNum int64, plusOneB
 		}
 	}
 
-	return nil
+	return challenged, nil
 }
 
 func (c *BasicClaimManager) distributeFees(claimID *big.Int) error {
@@ -0,0 +1,71 @@
+package eth
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	claimsSubmitted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "livepeer",
+		Subsystem: "claim_pipeline",
+		Name:      "claims_submitted_total",
+		Help:      "Number of ranges successfully submitted via ClaimWork or ClaimWorkBatch.",
+	})
+
+	verificationsChallenged = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "livepeer",
+		Subsystem: "claim_pipeline",
+		Name:      "verifications_challenged_total",
+		Help:      "Number of segments selected by shouldVerifySegment for on-chain verification, including ones whose upload or Verify transaction then failed.",
+	})
+
+	feesDistributed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "livepeer",
+		Subsystem: "claim_pipeline",
+		Name:      "fees_distributed_total",
+		Help:      "Number of ranges whose fees were successfully distributed.",
+	})
+
+	submitLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "livepeer",
+		Subsystem: "claim_pipeline",
+		Name:      "submit_claim_latency_seconds",
+		Help:      "Time spent in the submit-claim stage per batch.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	verifyLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "livepeer",
+		Subsystem: "claim_pipeline",
+		Name:      "verify_latency_seconds",
+		Help:      "Time spent in the verify stage per range.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	distributeLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "livepeer",
+		Subsystem: "claim_pipeline",
+		Name:      "distribute_fees_latency_seconds",
+		Help:      "Time spent in the distribute-fees stage per range.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	storeErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "livepeer",
+		Subsystem: "claim_pipeline",
+		Name:      "store_errors_total",
+		Help:      "Number of ClaimStore persistence failures (SaveReceipt, SaveClaim, or UpdateClaimState) that were logged and otherwise ignored.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		claimsSubmitted,
+		verificationsChallenged,
+		feesDistributed,
+		submitLatency,
+		verifyLatency,
+		distributeLatency,
+		storeErrors,
+	)
+}
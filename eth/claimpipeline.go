@@ -0,0 +1,365 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/livepeer/go-livepeer/eth/claimstore"
+)
+
+//BatchClaimant is an optional capability of LivepeerEthClient: a client that
+//implements it can pack multiple ranges sharing a job into a single
+//ClaimWork transaction where the on-chain contract permits it. A client
+//that doesn't implement it is submitted one range at a time, as before.
+type BatchClaimant interface {
+	ClaimWorkBatch(jobID *big.Int, ranges [][2]*big.Int, roots []common.Hash) (*gethTypes.Transaction, error)
+}
+
+//ClaimIDReporter is an optional capability of LivepeerEthClient: a client
+//that implements it can report the claim ID(s) a just-confirmed ClaimWork /
+//ClaimWorkBatch transaction was assigned - typically read back out of the
+//transaction's own receipt/logs - in the same order as the ranges/roots that
+//were submitted in it. A client that implements this lets BasicClaimManager
+//learn each range's claim ID straight from its own transaction, so submit
+//workers never need to coordinate with each other and stay concurrent even
+//with PipelineConfig.SubmitWorkers > 1. A client that doesn't implement it
+//falls back to BasicClaimManager serializing submissions under submitLock
+//and handing out claim IDs from a call-order counter instead.
+type ClaimIDReporter interface {
+	ClaimIDsForTx(tx *gethTypes.Transaction) ([]int64, error)
+}
+
+//PipelineConfig sizes the bounded worker pool backing each stage of a
+//ClaimPipeline.
+type PipelineConfig struct {
+	SubmitWorkers     int
+	VerifyWorkers     int
+	DistributeWorkers int
+	QueueSize         int
+	MaxBatchSize      int
+}
+
+//DefaultPipelineConfig is what NewBasicClaimManager uses unless overridden
+//with BasicClaimManager.SetPipelineConfig.
+var DefaultPipelineConfig = PipelineConfig{
+	SubmitWorkers:     2,
+	VerifyWorkers:     4,
+	DistributeWorkers: 2,
+	QueueSize:         16,
+	MaxBatchSize:      8,
+}
+
+//StageError associates a ClaimPipeline failure with the stage and segment
+//range it happened in, so a failure in one range doesn't block or obscure
+//the others.
+type StageError struct {
+	Stage    string
+	SegRange [2]int64
+	Err      error
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("claim pipeline: %v stage failed for range %v: %v", e.Stage, e.SegRange, e.Err)
+}
+
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+//claimJob carries a single range through the verify and distribute-fees
+//stages once it has a persisted claim record.
+type claimJob struct {
+	segRange [2]int64
+	rec      *claimstore.ClaimRecord
+}
+
+//ClaimPipeline runs the submit-claim, verify, and distribute-fees stages of
+//BasicClaimManager.ClaimVerifyAndDistributeFees as bounded worker pools
+//connected by buffered channels, instead of spawning one goroutine per
+//range with no way to bound concurrency or shut it down early.
+type ClaimPipeline struct {
+	cm  *BasicClaimManager
+	cfg PipelineConfig
+
+	submitCh     chan [2]int64
+	verifyCh     chan claimJob
+	distributeCh chan claimJob
+}
+
+//NewClaimPipeline creates a ClaimPipeline for cm, sizing its worker pools
+//and channel buffers from cfg.
+func NewClaimPipeline(cm *BasicClaimManager, cfg PipelineConfig) *ClaimPipeline {
+	return &ClaimPipeline{
+		cm:           cm,
+		cfg:          cfg,
+		submitCh:     make(chan [2]int64, cfg.QueueSize),
+		verifyCh:     make(chan claimJob, cfg.QueueSize),
+		distributeCh: make(chan claimJob, cfg.QueueSize),
+	}
+}
+
+//Run feeds ranges through the pipeline and blocks until every stage has
+//drained or ctx is canceled. It returns one *StageError per range that
+//failed in a stage, plus ctx.Err() itself (once) if ctx is canceled before
+//every range has been handed to the submit-claim stage; the ranges that
+//never got scheduled don't each produce an error of their own.
+func (p *ClaimPipeline) Run(ctx context.Context, ranges [][2]int64) []error {
+	var mu sync.Mutex
+	var errs []error
+	recordErr := func(err error) {
+		if err == nil {
+			return
+		}
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}
+
+	var submitWG, verifyWG, distributeWG sync.WaitGroup
+
+	distributeWG.Add(p.cfg.DistributeWorkers)
+	for i := 0; i < p.cfg.DistributeWorkers; i++ {
+		go p.runDistributeWorker(ctx, &distributeWG, recordErr)
+	}
+
+	verifyWG.Add(p.cfg.VerifyWorkers)
+	for i := 0; i < p.cfg.VerifyWorkers; i++ {
+		go p.runVerifyWorker(ctx, &verifyWG, recordErr)
+	}
+
+	submitWG.Add(p.cfg.SubmitWorkers)
+	for i := 0; i < p.cfg.SubmitWorkers; i++ {
+		go p.runSubmitWorker(ctx, &submitWG, recordErr)
+	}
+
+	func() {
+		for _, r := range ranges {
+			select {
+			case <-ctx.Done():
+				recordErr(ctx.Err())
+				return
+			case p.submitCh <- r:
+			}
+		}
+	}()
+	close(p.submitCh)
+
+	submitWG.Wait()
+	close(p.verifyCh)
+	verifyWG.Wait()
+	close(p.distributeCh)
+	distributeWG.Wait()
+
+	return errs
+}
+
+//RunRecovered resumes claims recovered from a ClaimStore (see Recover)
+//through the same bounded verify and distribute-fees worker pools, context
+//cancellation, and StageError aggregation as Run, feeding each claim in at
+//whichever stage its persisted state says it hasn't completed yet. This is
+//what keeps a process restart with many in-flight claims from falling back
+//to one goroutine per claim.
+func (p *ClaimPipeline) RunRecovered(ctx context.Context, recs []*claimstore.ClaimRecord) []error {
+	var mu sync.Mutex
+	var errs []error
+	recordErr := func(err error) {
+		if err == nil {
+			return
+		}
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}
+
+	var verifyWG, distributeWG sync.WaitGroup
+
+	distributeWG.Add(p.cfg.DistributeWorkers)
+	for i := 0; i < p.cfg.DistributeWorkers; i++ {
+		go p.runDistributeWorker(ctx, &distributeWG, recordErr)
+	}
+
+	verifyWG.Add(p.cfg.VerifyWorkers)
+	for i := 0; i < p.cfg.VerifyWorkers; i++ {
+		go p.runVerifyWorker(ctx, &verifyWG, recordErr)
+	}
+
+	func() {
+		for _, rec := range recs {
+			job := claimJob{segRange: rec.SegRange, rec: rec}
+
+			dest := p.verifyCh
+			if rec.State > claimstore.Claimed {
+				dest = p.distributeCh
+			}
+
+			select {
+			case <-ctx.Done():
+				recordErr(ctx.Err())
+				return
+			case dest <- job:
+			}
+		}
+	}()
+	close(p.verifyCh)
+	verifyWG.Wait()
+	close(p.distributeCh)
+	distributeWG.Wait()
+
+	return errs
+}
+
+func (p *ClaimPipeline) runSubmitWorker(ctx context.Context, wg *sync.WaitGroup, recordErr func(error)) {
+	defer wg.Done()
+
+	for {
+		batch, ok := p.drainSubmitBatch(ctx)
+		if !ok {
+			return
+		}
+		if len(batch) == 0 {
+			continue
+		}
+
+		merkles := make([]*rangeMerkle, 0, len(batch))
+		for _, segRange := range batch {
+			rm, err := p.cm.buildRangeMerkle(segRange)
+			if err != nil {
+				recordErr(&StageError{Stage: "submit-claim", SegRange: segRange, Err: err})
+				continue
+			}
+			merkles = append(merkles, rm)
+		}
+		if len(merkles) == 0 {
+			continue
+		}
+
+		start := time.Now()
+		results := p.cm.submitRangeBatch(merkles)
+		submitLatency.Observe(time.Since(start).Seconds())
+
+		for _, res := range results {
+			if res.err != nil {
+				recordErr(&StageError{Stage: "submit-claim", SegRange: res.segRange, Err: res.err})
+				continue
+			}
+			claimsSubmitted.Inc()
+
+			select {
+			case <-ctx.Done():
+				return
+			case p.verifyCh <- claimJob{segRange: res.segRange, rec: res.rec}:
+			}
+		}
+	}
+}
+
+//drainSubmitBatch blocks for at least one range, then opportunistically
+//grabs up to cfg.MaxBatchSize-1 more without blocking, so that a client
+//supporting BatchClaimant can pack them into a single transaction.
+func (p *ClaimPipeline) drainSubmitBatch(ctx context.Context) ([][2]int64, bool) {
+	select {
+	case <-ctx.Done():
+		return nil, false
+	case segRange, ok := <-p.submitCh:
+		if !ok {
+			return nil, false
+		}
+
+		batch := [][2]int64{segRange}
+		for len(batch) < p.cfg.MaxBatchSize {
+			select {
+			case next, ok := <-p.submitCh:
+				if !ok {
+					return batch, true
+				}
+				batch = append(batch, next)
+			default:
+				return batch, true
+			}
+		}
+		return batch, true
+	}
+}
+
+func (p *ClaimPipeline) runVerifyWorker(ctx context.Context, wg *sync.WaitGroup, recordErr func(error)) {
+	defer wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job, ok := <-p.verifyCh:
+			if !ok {
+				return
+			}
+
+			claimID := big.NewInt(job.rec.ClaimID)
+			claimBlock := big.NewInt(job.rec.ClaimBlock)
+
+			b, err := p.cm.client.Backend()
+			if err != nil {
+				recordErr(&StageError{Stage: "verify", SegRange: job.segRange, Err: err})
+				continue
+			}
+
+			// Wait one block for claimBlock + 1 to be mined
+			Wait(b, RpcTimeout, big.NewInt(1))
+
+			plusOneBlk, err := b.BlockByNumber(ctx, new(big.Int).Add(claimBlock, big.NewInt(1)))
+			if err != nil {
+				recordErr(&StageError{Stage: "verify", SegRange: job.segRange, Err: err})
+				continue
+			}
+
+			start := time.Now()
+			challenged, err := p.cm.verify(ctx, claimID, job.rec.ClaimBlock, plusOneBlk.Hash(), job.segRange)
+			verifyLatency.Observe(time.Since(start).Seconds())
+			if err != nil {
+				recordErr(&StageError{Stage: "verify", SegRange: job.segRange, Err: err})
+				continue
+			}
+			verificationsChallenged.Add(float64(challenged))
+
+			p.cm.setClaimState(job.rec.ClaimID, claimstore.Verified)
+
+			select {
+			case <-ctx.Done():
+				return
+			case p.distributeCh <- job:
+			}
+		}
+	}
+}
+
+func (p *ClaimPipeline) runDistributeWorker(ctx context.Context, wg *sync.WaitGroup, recordErr func(error)) {
+	defer wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job, ok := <-p.distributeCh:
+			if !ok {
+				return
+			}
+
+			claimID := big.NewInt(job.rec.ClaimID)
+
+			start := time.Now()
+			err := p.cm.distributeFees(claimID)
+			distributeLatency.Observe(time.Since(start).Seconds())
+			if err != nil {
+				recordErr(&StageError{Stage: "distribute-fees", SegRange: job.segRange, Err: err})
+				continue
+			}
+
+			p.cm.setClaimState(job.rec.ClaimID, claimstore.FeesDistributed)
+			feesDistributed.Inc()
+		}
+	}
+}
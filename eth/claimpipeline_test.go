@@ -0,0 +1,100 @@
+package eth
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDrainSubmitBatchGrabsUpToMaxBatchSizeWithoutBlocking(t *testing.T) {
+	p := &ClaimPipeline{
+		cfg:      PipelineConfig{MaxBatchSize: 3},
+		submitCh: make(chan [2]int64, 4),
+	}
+
+	p.submitCh <- [2]int64{0, 0}
+	p.submitCh <- [2]int64{1, 1}
+	p.submitCh <- [2]int64{2, 2}
+	p.submitCh <- [2]int64{3, 3}
+
+	batch, ok := p.drainSubmitBatch(context.Background())
+	if !ok {
+		t.Fatalf("expected a batch, got none")
+	}
+	if len(batch) != 3 {
+		t.Fatalf("got batch of %v, want MaxBatchSize (3)", len(batch))
+	}
+	if len(p.submitCh) != 1 {
+		t.Errorf("got %v ranges left on submitCh, want 1 (the 4th didn't fit in the batch)", len(p.submitCh))
+	}
+}
+
+func TestDrainSubmitBatchStopsShortWhenChannelEmpties(t *testing.T) {
+	p := &ClaimPipeline{
+		cfg:      PipelineConfig{MaxBatchSize: 5},
+		submitCh: make(chan [2]int64, 2),
+	}
+	p.submitCh <- [2]int64{0, 0}
+	p.submitCh <- [2]int64{1, 1}
+
+	batch, ok := p.drainSubmitBatch(context.Background())
+	if !ok {
+		t.Fatalf("expected a batch, got none")
+	}
+	if len(batch) != 2 {
+		t.Fatalf("got batch of %v, want 2 (fewer than MaxBatchSize since the channel ran dry)", len(batch))
+	}
+}
+
+func TestDrainSubmitBatchReturnsFalseOnClosedEmptyChannel(t *testing.T) {
+	p := &ClaimPipeline{
+		cfg:      PipelineConfig{MaxBatchSize: 5},
+		submitCh: make(chan [2]int64),
+	}
+	close(p.submitCh)
+
+	if _, ok := p.drainSubmitBatch(context.Background()); ok {
+		t.Fatalf("expected no batch from a closed, empty channel")
+	}
+}
+
+func TestRunWithNoRangesProducesNoErrors(t *testing.T) {
+	cm := &BasicClaimManager{pipelineCfg: DefaultPipelineConfig}
+	p := NewClaimPipeline(cm, DefaultPipelineConfig)
+
+	if errs := p.Run(context.Background(), nil); len(errs) != 0 {
+		t.Fatalf("got %v errors for an empty range set, want none: %v", len(errs), errs)
+	}
+}
+
+func TestRunReportsContextCancellationBeforeSubmission(t *testing.T) {
+	// No workers means nothing ever drains submitCh, so with ctx already
+	// canceled the feeding loop's select deterministically takes the
+	// ctx.Done() branch instead of racing a buffered channel send against
+	// it - and since no worker ever runs, this can't reach cm.client.
+	cfg := PipelineConfig{SubmitWorkers: 0, VerifyWorkers: 0, DistributeWorkers: 0, QueueSize: 0, MaxBatchSize: 1}
+	cm := &BasicClaimManager{pipelineCfg: cfg}
+	p := NewClaimPipeline(cm, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errs := p.Run(ctx, [][2]int64{{0, 0}})
+	if len(errs) == 0 {
+		t.Fatalf("expected a context-cancellation error, got none")
+	}
+	if errs[0] != context.Canceled {
+		t.Errorf("got error %v, want context.Canceled", errs[0])
+	}
+}
+
+func TestStageErrorWrapsUnderlyingErr(t *testing.T) {
+	underlying := context.Canceled
+	err := &StageError{Stage: "verify", SegRange: [2]int64{0, 1}, Err: underlying}
+
+	if err.Unwrap() != underlying {
+		t.Errorf("Unwrap() = %v, want %v", err.Unwrap(), underlying)
+	}
+	if err.Error() == "" {
+		t.Errorf("Error() returned an empty string")
+	}
+}
@@ -0,0 +1,125 @@
+package claimstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/boltdb/bolt"
+)
+
+var claimsBucket = []byte("claims")
+
+// BoltClaimStore is the default ClaimStore, backed by a local BoltDB file.
+type BoltClaimStore struct {
+	db *bolt.DB
+}
+
+// NewBoltClaimStore opens (creating if necessary) a BoltDB-backed ClaimStore
+// at path.
+func NewBoltClaimStore(path string) (*BoltClaimStore, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(claimsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltClaimStore{db: db}, nil
+}
+
+func (s *BoltClaimStore) SaveReceipt(prefix string, rec *ReceiptRecord) error {
+	data, err := marshalReceipt(rec)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(claimsBucket).Put(receiptKey(prefix, rec.SeqNo), data)
+	})
+}
+
+func (s *BoltClaimStore) Receipts(prefix string) (map[int64]*ReceiptRecord, error) {
+	recs := make(map[int64]*ReceiptRecord)
+	p := receiptKeyPrefix(prefix)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(claimsBucket).Cursor()
+		for k, v := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, v = c.Next() {
+			rec := &ReceiptRecord{}
+			if err := json.Unmarshal(v, rec); err != nil {
+				return err
+			}
+			recs[rec.SeqNo] = rec
+		}
+		return nil
+	})
+
+	return recs, err
+}
+
+func (s *BoltClaimStore) SaveClaim(prefix string, rec *ClaimRecord) error {
+	data, err := marshalClaim(rec)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(claimsBucket).Put(claimKey(prefix, rec.ClaimID), data)
+	})
+}
+
+func (s *BoltClaimStore) UpdateClaimState(prefix string, claimID int64, state ClaimState) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(claimsBucket)
+		k := claimKey(prefix, claimID)
+
+		v := b.Get(k)
+		if v == nil {
+			return fmt.Errorf("claimstore: no claim %v found under %v", claimID, prefix)
+		}
+
+		rec := &ClaimRecord{}
+		if err := json.Unmarshal(v, rec); err != nil {
+			return err
+		}
+		rec.State = state
+
+		data, err := marshalClaim(rec)
+		if err != nil {
+			return err
+		}
+
+		return b.Put(k, data)
+	})
+}
+
+func (s *BoltClaimStore) Claims(prefix string) (map[int64]*ClaimRecord, error) {
+	recs := make(map[int64]*ClaimRecord)
+	p := claimKeyPrefix(prefix)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(claimsBucket).Cursor()
+		for k, v := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, v = c.Next() {
+			rec := &ClaimRecord{}
+			if err := json.Unmarshal(v, rec); err != nil {
+				return err
+			}
+			recs[rec.ClaimID] = rec
+		}
+		return nil
+	})
+
+	return recs, err
+}
+
+func (s *BoltClaimStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,107 @@
+package claimstore
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func newTestBoltStore(t *testing.T) *BoltClaimStore {
+	path := filepath.Join(t.TempDir(), "claims.db")
+	store, err := NewBoltClaimStore(path)
+	if err != nil {
+		t.Fatalf("NewBoltClaimStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestBoltClaimStoreReceiptRoundTrip(t *testing.T) {
+	store := newTestBoltStore(t)
+	prefix := "job-1"
+
+	rec := &ReceiptRecord{
+		SeqNo:       5,
+		SegData:     []byte("data"),
+		DataHash:    []byte("hash"),
+		TDataHashes: map[string][]byte{"P240p": []byte("t-hash")},
+		BSig:        []byte("sig"),
+	}
+	if err := store.SaveReceipt(prefix, rec); err != nil {
+		t.Fatalf("SaveReceipt: %v", err)
+	}
+
+	recs, err := store.Receipts(prefix)
+	if err != nil {
+		t.Fatalf("Receipts: %v", err)
+	}
+	got, ok := recs[5]
+	if !ok {
+		t.Fatalf("receipt for seqNo 5 not found")
+	}
+	if string(got.SegData) != "data" || string(got.TDataHashes["P240p"]) != "t-hash" {
+		t.Errorf("round-tripped receipt mismatch: %+v", got)
+	}
+}
+
+func TestBoltClaimStoreClaimRoundTripAndStateUpdate(t *testing.T) {
+	store := newTestBoltStore(t)
+	prefix := "job-1"
+
+	rec := &ClaimRecord{
+		ClaimID:      3,
+		SegRange:     [2]int64{0, 4},
+		State:        Claimed,
+		ClaimBlock:   100,
+		MerkleProofs: map[int64][]byte{0: []byte("proof")},
+		ConcatHashes: map[int64][]byte{0: []byte("concat")},
+	}
+	if err := store.SaveClaim(prefix, rec); err != nil {
+		t.Fatalf("SaveClaim: %v", err)
+	}
+
+	if err := store.UpdateClaimState(prefix, rec.ClaimID, Verified); err != nil {
+		t.Fatalf("UpdateClaimState: %v", err)
+	}
+
+	claims, err := store.Claims(prefix)
+	if err != nil {
+		t.Fatalf("Claims: %v", err)
+	}
+	got, ok := claims[3]
+	if !ok {
+		t.Fatalf("claim 3 not found")
+	}
+	if got.State != Verified {
+		t.Errorf("got state %v, want %v", got.State, Verified)
+	}
+	if got.SegRange != rec.SegRange {
+		t.Errorf("got segRange %v, want %v", got.SegRange, rec.SegRange)
+	}
+}
+
+func TestBoltClaimStoreUpdateClaimStateMissingClaim(t *testing.T) {
+	store := newTestBoltStore(t)
+
+	if err := store.UpdateClaimState("job-1", 99, Verified); err == nil {
+		t.Fatalf("expected error updating state of a claim that was never saved")
+	}
+}
+
+func TestBoltClaimStoreScopesByPrefix(t *testing.T) {
+	store := newTestBoltStore(t)
+
+	if err := store.SaveReceipt("job-1", &ReceiptRecord{SeqNo: 0}); err != nil {
+		t.Fatalf("SaveReceipt: %v", err)
+	}
+	if err := store.SaveReceipt("job-2", &ReceiptRecord{SeqNo: 0}); err != nil {
+		t.Fatalf("SaveReceipt: %v", err)
+	}
+
+	recs, err := store.Receipts("job-1")
+	if err != nil {
+		t.Fatalf("Receipts: %v", err)
+	}
+	if len(recs) != 1 {
+		t.Fatalf("got %v receipts under job-1, want 1 (job-2's receipt leaked across prefixes)", len(recs))
+	}
+}
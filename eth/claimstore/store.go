@@ -0,0 +1,94 @@
+// Package claimstore persists the claim lifecycle of a BasicClaimManager job
+// (receipts received, claims submitted on-chain, verification and fee
+// distribution progress) so that an in-flight job can be recovered after the
+// transcoder process crashes or is restarted.
+package claimstore
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ClaimState tracks the lifecycle of a single on-chain claim.
+type ClaimState int
+
+const (
+	Pending ClaimState = iota
+	Claimed
+	Verified
+	FeesDistributed
+)
+
+func (s ClaimState) String() string {
+	switch s {
+	case Pending:
+		return "Pending"
+	case Claimed:
+		return "Claimed"
+	case Verified:
+		return "Verified"
+	case FeesDistributed:
+		return "FeesDistributed"
+	default:
+		return "Unknown"
+	}
+}
+
+// ReceiptRecord is the persisted form of a single segment's receipt data,
+// written as soon as BasicClaimManager.AddReceipt is called for that segment.
+type ReceiptRecord struct {
+	SeqNo       int64
+	SegData     []byte
+	DataHash    []byte
+	TDataHashes map[string][]byte // keyed by video profile name
+	BSig        []byte
+}
+
+// ClaimRecord is the persisted form of a single on-chain claim and its
+// progress through verification and fee distribution.
+type ClaimRecord struct {
+	ClaimID      int64
+	SegRange     [2]int64
+	State        ClaimState
+	ClaimBlock   int64
+	MerkleProofs map[int64][]byte // seqNo -> merkle proof bytes
+	ConcatHashes map[int64][]byte // seqNo -> claimConcatTDatahash
+}
+
+// ClaimStore persists the claim state of a job, keyed by a caller-supplied
+// job-scoped prefix, so it can be rehydrated after a crash.
+type ClaimStore interface {
+	// SaveReceipt persists a receipt for a single segment.
+	SaveReceipt(prefix string, rec *ReceiptRecord) error
+	// Receipts returns every persisted receipt under prefix, keyed by seqNo.
+	Receipts(prefix string) (map[int64]*ReceiptRecord, error)
+
+	// SaveClaim persists (or overwrites) a claim record.
+	SaveClaim(prefix string, rec *ClaimRecord) error
+	// UpdateClaimState updates just the state of an already-persisted claim.
+	UpdateClaimState(prefix string, claimID int64, state ClaimState) error
+	// Claims returns every persisted claim under prefix, keyed by claim ID.
+	Claims(prefix string) (map[int64]*ClaimRecord, error)
+
+	Close() error
+}
+
+func receiptKey(prefix string, seqNo int64) []byte {
+	return []byte(fmt.Sprintf("%s/receipt/%020d", prefix, seqNo))
+}
+
+func receiptKeyPrefix(prefix string) []byte {
+	return []byte(fmt.Sprintf("%s/receipt/", prefix))
+}
+
+func claimKey(prefix string, claimID int64) []byte {
+	return []byte(fmt.Sprintf("%s/claim/%020d", prefix, claimID))
+}
+
+func claimKeyPrefix(prefix string) []byte {
+	return []byte(fmt.Sprintf("%s/claim/", prefix))
+}
+
+func marshalReceipt(rec *ReceiptRecord) ([]byte, error) { return json.Marshal(rec) }
+
+func marshalClaim(rec *ClaimRecord) ([]byte, error) { return json.Marshal(rec) }
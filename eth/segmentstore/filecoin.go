@@ -0,0 +1,40 @@
+package segmentstore
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DealClient is the minimal subset of a go-fil-markets-style storage client
+// SegmentStore needs to start and check on a Filecoin storage deal.
+type DealClient interface {
+	// StartDeal stores data with a miner and returns the resulting deal's
+	// CID, which doubles as the SegmentStore URI for this backend.
+	StartDeal(ctx context.Context, data []byte) (dealCid string, err error)
+	// DealStatus reports whether dealCid is still active (i.e. retrievable).
+	DealStatus(ctx context.Context, dealCid string) (active bool, err error)
+}
+
+// FilecoinStore is a SegmentStore backed by a Filecoin storage deal.
+type FilecoinStore struct {
+	client DealClient
+}
+
+// NewFilecoinStore creates a SegmentStore that stores segment data via client.
+func NewFilecoinStore(client DealClient) *FilecoinStore {
+	return &FilecoinStore{client: client}
+}
+
+func (s *FilecoinStore) Put(ctx context.Context, data []byte) (string, Scheme, [32]byte, error) {
+	dealCid, err := s.client.StartDeal(ctx, data)
+	if err != nil {
+		return "", SchemeFilecoin, [32]byte{}, err
+	}
+
+	return dealCid, SchemeFilecoin, [32]byte(crypto.Keccak256Hash(data)), nil
+}
+
+func (s *FilecoinStore) Has(ctx context.Context, uri string) (bool, error) {
+	return s.client.DealStatus(ctx, uri)
+}
@@ -0,0 +1,39 @@
+package segmentstore
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/livepeer/go-livepeer/ipfs"
+)
+
+// IPFSStore adapts an ipfs.IpfsApi into a SegmentStore. It is the backend
+// BasicClaimManager.verify used directly before SegmentStore existed.
+type IPFSStore struct {
+	api ipfs.IpfsApi
+}
+
+// NewIPFSStore creates a SegmentStore backed by an existing IPFS API client.
+func NewIPFSStore(api ipfs.IpfsApi) *IPFSStore {
+	return &IPFSStore{api: api}
+}
+
+func (s *IPFSStore) Put(ctx context.Context, data []byte) (string, Scheme, [32]byte, error) {
+	hash, err := s.api.Add(bytes.NewReader(data))
+	if err != nil {
+		return "", SchemeIPFS, [32]byte{}, err
+	}
+
+	return hash, SchemeIPFS, [32]byte(crypto.Keccak256Hash(data)), nil
+}
+
+func (s *IPFSStore) Has(ctx context.Context, uri string) (bool, error) {
+	rc, err := s.api.Cat(uri)
+	if err != nil {
+		return false, nil
+	}
+	rc.Close()
+
+	return true, nil
+}
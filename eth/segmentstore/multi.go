@@ -0,0 +1,88 @@
+package segmentstore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// RetryPolicy controls the exponential backoff MultiStore applies to a
+// single backend's Put before falling through to the next backend.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+}
+
+// DefaultRetryPolicy retries a backend twice, waiting 500ms then 1s, before
+// MultiStore falls through to the next one.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: 500 * time.Millisecond}
+
+// MultiStore tries a list of backends in order, retrying each with
+// exponential backoff before falling through to the next one. This replaces
+// silently dropping a verification when a single backend's upload fails.
+type MultiStore struct {
+	backends []SegmentStore
+	retry    RetryPolicy
+}
+
+// NewMultiStore creates a MultiStore that tries backends in order, applying
+// retry to each one before falling through to the next.
+func NewMultiStore(retry RetryPolicy, backends ...SegmentStore) *MultiStore {
+	return &MultiStore{backends: backends, retry: retry}
+}
+
+func (m *MultiStore) Put(ctx context.Context, data []byte) (string, Scheme, [32]byte, error) {
+	var lastErr error
+
+	for _, backend := range m.backends {
+		uri, scheme, contentHash, err := putWithRetry(ctx, backend, data, m.retry)
+		if err == nil {
+			return uri, scheme, contentHash, nil
+		}
+
+		glog.Errorf("Error uploading segment data, falling back to next store: %v", err)
+		lastErr = err
+	}
+
+	return "", 0, [32]byte{}, fmt.Errorf("segmentstore: all backends failed, last error: %v", lastErr)
+}
+
+func putWithRetry(ctx context.Context, backend SegmentStore, data []byte, retry RetryPolicy) (string, Scheme, [32]byte, error) {
+	var uri string
+	var scheme Scheme
+	var contentHash [32]byte
+	var err error
+
+	delay := retry.BaseDelay
+	for attempt := 0; attempt < retry.MaxAttempts; attempt++ {
+		uri, scheme, contentHash, err = backend.Put(ctx, data)
+		if err == nil {
+			return uri, scheme, contentHash, nil
+		}
+
+		if attempt+1 == retry.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", scheme, [32]byte{}, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+
+	return "", scheme, [32]byte{}, err
+}
+
+func (m *MultiStore) Has(ctx context.Context, uri string) (bool, error) {
+	for _, backend := range m.backends {
+		if ok, err := backend.Has(ctx, uri); err == nil && ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,93 @@
+package segmentstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeStore is a SegmentStore whose Put fails its first failAttempts calls
+// before succeeding (or never succeeds, if failAttempts < 0), so tests can
+// drive MultiStore's retry and fallback paths deterministically.
+type fakeStore struct {
+	scheme       Scheme
+	failAttempts int
+	puts         int
+	hasURIs      map[string]bool
+}
+
+func (f *fakeStore) Put(ctx context.Context, data []byte) (string, Scheme, [32]byte, error) {
+	f.puts++
+	if f.failAttempts < 0 || f.puts <= f.failAttempts {
+		return "", f.scheme, [32]byte{}, errors.New("put failed")
+	}
+	return "uri", f.scheme, [32]byte{0x1}, nil
+}
+
+func (f *fakeStore) Has(ctx context.Context, uri string) (bool, error) {
+	return f.hasURIs[uri], nil
+}
+
+var fastRetry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+func TestMultiStoreRetriesBeforeFallingThrough(t *testing.T) {
+	first := &fakeStore{scheme: SchemeIPFS, failAttempts: 2}
+	second := &fakeStore{scheme: SchemeS3, failAttempts: 0}
+
+	m := NewMultiStore(fastRetry, first, second)
+	uri, scheme, _, err := m.Put(context.Background(), []byte("data"))
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if scheme != SchemeIPFS || uri != "uri" {
+		t.Errorf("got scheme %v uri %v, want first backend to succeed on retry", scheme, uri)
+	}
+	if first.puts != 3 {
+		t.Errorf("got %v attempts against first backend, want 3 (2 failures + 1 success)", first.puts)
+	}
+	if second.puts != 0 {
+		t.Errorf("second backend was tried even though the first succeeded after retrying")
+	}
+}
+
+func TestMultiStoreFallsThroughWhenABackendExhaustsRetries(t *testing.T) {
+	first := &fakeStore{scheme: SchemeIPFS, failAttempts: -1}
+	second := &fakeStore{scheme: SchemeS3, failAttempts: 0}
+
+	m := NewMultiStore(fastRetry, first, second)
+	_, scheme, _, err := m.Put(context.Background(), []byte("data"))
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if scheme != SchemeS3 {
+		t.Errorf("got scheme %v, want fallback to second backend", scheme)
+	}
+	if first.puts != fastRetry.MaxAttempts {
+		t.Errorf("got %v attempts against first backend, want all %v retries exhausted", first.puts, fastRetry.MaxAttempts)
+	}
+}
+
+func TestMultiStoreReturnsErrorWhenAllBackendsFail(t *testing.T) {
+	first := &fakeStore{scheme: SchemeIPFS, failAttempts: -1}
+	second := &fakeStore{scheme: SchemeS3, failAttempts: -1}
+
+	m := NewMultiStore(fastRetry, first, second)
+	if _, _, _, err := m.Put(context.Background(), []byte("data")); err == nil {
+		t.Fatalf("expected an error when every backend fails")
+	}
+}
+
+func TestMultiStoreHasChecksEveryBackend(t *testing.T) {
+	first := &fakeStore{hasURIs: map[string]bool{}}
+	second := &fakeStore{hasURIs: map[string]bool{"uri": true}}
+
+	m := NewMultiStore(fastRetry, first, second)
+	ok, err := m.Has(context.Background(), "uri")
+	if err != nil {
+		t.Fatalf("Has: %v", err)
+	}
+	if !ok {
+		t.Errorf("got false, want true since the second backend has the uri")
+	}
+}
@@ -0,0 +1,76 @@
+package segmentstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// S3API is the minimal subset of an S3-compatible client SegmentStore needs.
+// Satisfied by *s3.S3 from aws-sdk-go (via a thin wrapper) or any
+// S3-compatible SDK.
+type S3API interface {
+	PutObject(ctx context.Context, bucket, key string, body io.Reader) error
+	HeadObject(ctx context.Context, bucket, key string) error
+}
+
+// S3Store is a SegmentStore backed by an S3-compatible bucket. Objects are
+// keyed by the keccak256 hash of their content so re-uploading the same
+// segment data is a no-op.
+type S3Store struct {
+	api    S3API
+	bucket string
+	prefix string
+}
+
+// NewS3Store creates a SegmentStore that uploads to bucket under prefix.
+func NewS3Store(api S3API, bucket string, prefix string) *S3Store {
+	return &S3Store{api: api, bucket: bucket, prefix: prefix}
+}
+
+func (s *S3Store) Put(ctx context.Context, data []byte) (string, Scheme, [32]byte, error) {
+	contentHash := crypto.Keccak256Hash(data)
+	key := s.objectKey(contentHash.Hex())
+
+	if err := s.api.PutObject(ctx, s.bucket, key, bytes.NewReader(data)); err != nil {
+		return "", SchemeS3, [32]byte{}, err
+	}
+
+	return s.uri(key), SchemeS3, [32]byte(contentHash), nil
+}
+
+func (s *S3Store) Has(ctx context.Context, uri string) (bool, error) {
+	key, err := s.keyFromURI(uri)
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.api.HeadObject(ctx, s.bucket, key); err != nil {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (s *S3Store) objectKey(contentHashHex string) string {
+	if s.prefix == "" {
+		return contentHashHex
+	}
+	return s.prefix + "/" + contentHashHex
+}
+
+func (s *S3Store) uri(key string) string {
+	return fmt.Sprintf("s3://%s/%s", s.bucket, key)
+}
+
+func (s *S3Store) keyFromURI(uri string) (string, error) {
+	prefix := fmt.Sprintf("s3://%s/", s.bucket)
+	if !strings.HasPrefix(uri, prefix) {
+		return "", fmt.Errorf("segmentstore: %v is not an s3 URI for bucket %v", uri, s.bucket)
+	}
+	return strings.TrimPrefix(uri, prefix), nil
+}
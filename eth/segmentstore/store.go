@@ -0,0 +1,46 @@
+// Package segmentstore abstracts the storage backend BasicClaimManager uses
+// to publish a challenged segment's data so a verifier can fetch it back
+// during the on-chain verification window. IPFS, an S3-compatible bucket,
+// and a Filecoin storage deal are all valid backends; MultiStore chains
+// several of them together with retries so a single backend's outage
+// doesn't silently drop a verification.
+package segmentstore
+
+import (
+	"context"
+)
+
+// Scheme identifies which backend produced a SegmentStore URI. It is
+// recorded on-chain alongside the URI as a single byte so a verifier knows
+// how to fetch the data back.
+type Scheme byte
+
+const (
+	SchemeIPFS Scheme = iota
+	SchemeS3
+	SchemeFilecoin
+)
+
+func (s Scheme) String() string {
+	switch s {
+	case SchemeIPFS:
+		return "ipfs"
+	case SchemeS3:
+		return "s3"
+	case SchemeFilecoin:
+		return "filecoin"
+	default:
+		return "unknown"
+	}
+}
+
+// SegmentStore publishes challenged segment data so it can be fetched back
+// by a verifier during the on-chain verification window.
+type SegmentStore interface {
+	// Put uploads data and returns a URI identifying it, the Scheme needed
+	// to retrieve it, and a keccak256 content hash a verifier can check the
+	// fetched bytes against.
+	Put(ctx context.Context, data []byte) (uri string, scheme Scheme, contentHash [32]byte, err error)
+	// Has reports whether uri is still retrievable from this backend.
+	Has(ctx context.Context, uri string) (bool, error)
+}
@@ -0,0 +1,97 @@
+// Package testvectors holds deterministic golden-vector test corpora for the
+// two most error-prone, consensus-relevant pieces of the claim protocol:
+// BasicClaimManager.makeRanges (segment contiguity + all-profiles-present
+// partitioning) and BasicClaimManager.shouldVerifySegment (the
+// keccak256(blkNum+1 || blkHash || seqNo) mod verifyRate sampling). A
+// refactor of either must keep matching this corpus, since a silent change
+// in range partitioning or verification sampling changes which claims and
+// challenges are valid on-chain.
+//
+// MerkleRootVector is a golden test case for the merkle root built over a
+// canonical set of receipt hashes during claim submission. The real scheme
+// lives in eth/types.NewMerkleTree, which this chunk of the tree doesn't
+// contain, so the merkleRoots vectors in data/claim_vectors.json are instead computed against
+// ReferenceMerkleRoot, a concrete standalone binary merkle scheme defined in
+// this package purely so these vectors have something to test. Once
+// eth/types is available here, vectors generated against the real
+// NewMerkleTree should replace these.
+package testvectors
+
+import (
+	"encoding/json"
+	"io/ioutil"
+)
+
+// SegmentVector describes one segment's state going into makeRanges: which
+// profiles (by name) have a transcoded data hash recorded for it.
+type SegmentVector struct {
+	SeqNo           int64    `json:"seqNo"`
+	ProfilesPresent []string `json:"profilesPresent"`
+}
+
+// RangeVector is a golden test case for makeRanges: a (possibly sparse,
+// possibly gappy) set of unclaimed segments over a fixed profile set, and
+// the exact ranges makeRanges must produce for it.
+type RangeVector struct {
+	Name           string          `json:"name"`
+	Profiles       []string        `json:"profiles"`
+	Segments       []SegmentVector `json:"segments"`
+	ExpectedRanges [][2]int64      `json:"expectedRanges"`
+}
+
+// VerifySampleVector is a golden test case for shouldVerifySegment: given a
+// claimed range and verification parameters, which segments in the range
+// get challenged. ProbeSeqNo optionally names one extra seqNo, outside
+// [RangeStart, RangeEnd], that must not be challenged; the range itself is
+// still sampled and checked against ExpectedChallenged as usual.
+type VerifySampleVector struct {
+	Name               string  `json:"name"`
+	RangeStart         int64   `json:"rangeStart"`
+	RangeEnd           int64   `json:"rangeEnd"`
+	ClaimBlkNum        int64   `json:"claimBlkNum"`
+	PlusOneBlkHash     string  `json:"plusOneBlkHash"` // hex-encoded, no 0x prefix
+	VerifyRate         uint64  `json:"verifyRate"`
+	ExpectedChallenged []int64 `json:"expectedChallenged"`
+	ProbeSeqNo         *int64  `json:"probeSeqNo,omitempty"`
+}
+
+// MerkleRootVector is a golden test case for the merkle root built over a
+// canonical set of receipt hashes during claim submission.
+type MerkleRootVector struct {
+	Name          string   `json:"name"`
+	ReceiptHashes []string `json:"receiptHashes"` // hex-encoded, in order
+	ExpectedRoot  string   `json:"expectedRoot"`  // hex-encoded
+}
+
+// Corpus is the full set of golden vectors for one protocol version.
+type Corpus struct {
+	ProtocolVersion string               `json:"protocolVersion"`
+	Ranges          []RangeVector        `json:"ranges"`
+	VerifySamples   []VerifySampleVector `json:"verifySamples"`
+	MerkleRoots     []MerkleRootVector   `json:"merkleRoots"`
+}
+
+// Load reads a Corpus from a JSON file at path.
+func Load(path string) (*Corpus, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Corpus{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Save writes c as indented JSON to path.
+func Save(path string, c *Corpus) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,38 @@
+package testvectors
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ReferenceMerkleRoot computes a simple binary merkle root over leaves:
+// pairwise keccak256(left || right) at each level, duplicating a level's
+// last node when its count is odd, repeated until one hash remains.
+//
+// This is a concrete, checkable stand-in for the real scheme built during
+// claim submission - eth/types.NewMerkleTree, which this slice of the tree
+// doesn't contain - so the merkleRoots vectors in data/claim_vectors.json have something to test
+// against instead of nothing. It is not verified to match NewMerkleTree's
+// actual leaf ordering or padding; once eth/types is available here, vectors
+// generated against it should replace these.
+func ReferenceMerkleRoot(leaves []common.Hash) common.Hash {
+	if len(leaves) == 0 {
+		return common.Hash{}
+	}
+
+	level := make([]common.Hash, len(leaves))
+	copy(level, leaves)
+
+	for len(level) > 1 {
+		if len(level)%2 == 1 {
+			level = append(level, level[len(level)-1])
+		}
+		next := make([]common.Hash, len(level)/2)
+		for i := range next {
+			next[i] = crypto.Keccak256Hash(level[2*i].Bytes(), level[2*i+1].Bytes())
+		}
+		level = next
+	}
+
+	return level[0]
+}